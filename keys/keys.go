@@ -0,0 +1,52 @@
+// Package keys derives Ed25519 signing keys and X25519 agreement keys from
+// an sskg.Seq's current position, so callers who want to sign log entries or
+// establish forward-secure channels don't have to roll their own KDF around
+// Seq.Key.
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+
+	"code.google.com/p/go.crypto/hkdf"
+
+	"github.com/codahale/sskg"
+)
+
+var (
+	ed25519Label = []byte("ed25519")
+	x25519Label  = []byte("x25519")
+)
+
+// SigningKey derives an Ed25519 private key from seq's current key. It
+// treats the derived material as an RFC 8032 seed, the same pattern used by
+// ed25519.PrivateKey.Seed.
+func SigningKey(seq sskg.Seq) ed25519.PrivateKey {
+	seed := derive(seq, ed25519Label, ed25519.SeedSize)
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+// AgreementKey derives a clamped X25519 scalar from seq's current key.
+func AgreementKey(seq sskg.Seq) [32]byte {
+	var key [32]byte
+	copy(key[:], derive(seq, x25519Label, 32))
+	clamp(&key)
+	return key
+}
+
+// derive expands seq's current key into size bytes of key material, using
+// label to domain-separate it from the Seq's own MAC key and from keys
+// derived with other labels.
+func derive(seq sskg.Seq, label []byte, size int) []byte {
+	out := make([]byte, size)
+	kdf := hkdf.New(sha256.New, seq.Key(32), nil, label)
+	_, _ = kdf.Read(out)
+	return out
+}
+
+// clamp applies the X25519 scalar clamping from RFC 7748.
+func clamp(k *[32]byte) {
+	k[0] &= 248
+	k[31] &= 127
+	k[31] |= 64
+}
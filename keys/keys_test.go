@@ -0,0 +1,59 @@
+package keys_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/codahale/sskg"
+	"github.com/codahale/sskg/keys"
+)
+
+func TestSigningKeyDeterministic(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	seq.Seek(5)
+
+	a := keys.SigningKey(seq)
+	b := keys.SigningKey(seq)
+
+	if !a.Equal(b) {
+		t.Error("SigningKey was not deterministic for a fixed Seq position")
+	}
+}
+
+func TestSigningKeyChangesWithPosition(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	a := keys.SigningKey(seq)
+
+	seq.Next()
+	b := keys.SigningKey(seq)
+
+	if a.Equal(b) {
+		t.Error("SigningKey did not change across Seq positions")
+	}
+}
+
+func TestAgreementKeyIsClamped(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	key := keys.AgreementKey(seq)
+
+	if key[0]&0x07 != 0 || key[31]&0x80 != 0 || key[31]&0x40 == 0 {
+		t.Errorf("AgreementKey was not clamped: %#v", key)
+	}
+}
+
+func TestKeysAreDomainSeparated(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+
+	sk := keys.SigningKey(seq)
+	ak := keys.AgreementKey(seq)
+
+	if bytes.Equal(sk.Seed(), ak[:]) {
+		t.Error("signing and agreement keys were not domain-separated")
+	}
+
+	if !ed25519.Verify(sk.Public().(ed25519.PublicKey), []byte("msg"), ed25519.Sign(sk, []byte("msg"))) {
+		t.Error("derived signing key did not produce a verifiable signature")
+	}
+}
@@ -0,0 +1,40 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// An Encoder writes a stream of Records as newline-delimited JSON, suitable
+// for appending to or rotating a log file on disk.
+type Encoder struct {
+	enc *json.Encoder
+}
+
+// NewEncoder returns an Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{enc: json.NewEncoder(w)}
+}
+
+// Encode writes r to the stream.
+func (e *Encoder) Encode(r Record) error {
+	return e.enc.Encode(r)
+}
+
+// A Decoder reads a stream of Records written by an Encoder.
+type Decoder struct {
+	dec *json.Decoder
+}
+
+// NewDecoder returns a Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r)}
+}
+
+// Decode reads the next Record from the stream. It returns io.EOF once the
+// stream is exhausted.
+func (d *Decoder) Decode() (Record, error) {
+	var r Record
+	err := d.dec.Decode(&r)
+	return r, err
+}
@@ -0,0 +1,147 @@
+// Package log provides a forward-secure, append-only log authenticated with
+// an sskg.Seq. Each entry is tagged with an HMAC keyed by the Seq's current
+// key before the Seq advances, so an attacker who later compromises the log
+// cannot forge or modify entries written in the past.
+package log
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"github.com/codahale/sskg"
+)
+
+// A Record is a single authenticated entry in a Log.
+type Record struct {
+	Index uint64 `json:"index"`
+	Entry []byte `json:"entry"`
+	Tag   []byte `json:"tag"`
+}
+
+// A Log is an append-only log of entries, each authenticated with a key
+// drawn from an sskg.Seq.
+type Log struct {
+	seq   sskg.Seq
+	alg   func() hash.Hash
+	index uint64
+	prev  []byte
+}
+
+// New returns a new Log which authenticates entries with keys drawn from seq
+// using alg.
+func New(seq sskg.Seq, alg func() hash.Hash) *Log {
+	return &Log{seq: seq, alg: alg}
+}
+
+// Append authenticates entry with the Log's current key, advances the
+// underlying Seq, and returns the resulting Record.
+func (l *Log) Append(entry []byte) Record {
+	tag := tag(l.alg, l.seq.Key(l.alg().Size()), l.index, l.prev, entry)
+	r := Record{Index: l.index, Entry: entry, Tag: tag}
+
+	l.seq.Next()
+	l.index++
+	l.prev = tag
+
+	return r
+}
+
+// Verify checks records against the key the Log's Seq held at each record's
+// index, starting at startIndex and skipping any records before it. A
+// non-zero startIndex requires records to also contain the record at
+// startIndex-1, whose tag seeds the chain; it returns an error describing
+// the first record that fails to verify.
+//
+// Verify seeks l's Seq from its current position, so l must be a Log built
+// from the same origin Seq used to produce records (e.g. a freshly
+// constructed Log, not the Log that appended them, which has since advanced
+// past index 0).
+func (l *Log) Verify(records []Record, startIndex uint64) error {
+	var prev []byte
+	if startIndex > 0 {
+		i, ok := indexOf(records, startIndex-1)
+		if !ok {
+			return fmt.Errorf("sskg/log: records does not contain record %d, needed to verify from startIndex %d", startIndex-1, startIndex)
+		}
+		prev = records[i].Tag
+	}
+
+	seq := l.seq.Clone()
+	seq.Seek(int(startIndex))
+
+	for _, r := range records {
+		if r.Index < startIndex {
+			continue
+		}
+
+		want := tag(l.alg, seq.Key(l.alg().Size()), r.Index, prev, r.Entry)
+		if !hmac.Equal(want, r.Tag) {
+			return fmt.Errorf("sskg/log: record %d failed verification", r.Index)
+		}
+
+		seq.Next()
+		prev = r.Tag
+	}
+
+	return nil
+}
+
+// VerifyAt checks the tag of records[i] using sskg.Seq.Seek to derive its key
+// directly, without replaying the records preceding it. This is the O(log N)
+// single-entry audit that makes an SSKG-backed log preferable to a hash
+// chain: the caller pays for one Seek instead of i calls to Next. If
+// records[i].Index is not 0, records must also contain the record at
+// Index-1, whose tag seeds the chain.
+//
+// Like Verify, VerifyAt seeks l's Seq from its current position, so l must
+// be built from the same origin Seq used to produce records.
+func (l *Log) VerifyAt(records []Record, i int) error {
+	if i < 0 || i >= len(records) {
+		return fmt.Errorf("sskg/log: index %d out of range", i)
+	}
+
+	r := records[i]
+
+	var prev []byte
+	if r.Index > 0 {
+		j, ok := indexOf(records, r.Index-1)
+		if !ok {
+			return fmt.Errorf("sskg/log: records does not contain record %d, needed to verify record %d", r.Index-1, r.Index)
+		}
+		prev = records[j].Tag
+	}
+
+	seq := l.seq.Clone()
+	seq.Seek(int(r.Index))
+
+	want := tag(l.alg, seq.Key(l.alg().Size()), r.Index, prev, r.Entry)
+	if !hmac.Equal(want, r.Tag) {
+		return fmt.Errorf("sskg/log: record %d failed verification", r.Index)
+	}
+
+	return nil
+}
+
+func indexOf(records []Record, index uint64) (int, bool) {
+	for i, r := range records {
+		if r.Index == index {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func tag(alg func() hash.Hash, key []byte, index uint64, prev, entry []byte) []byte {
+	h := hmac.New(alg, key)
+
+	var idx [8]byte
+	binary.BigEndian.PutUint64(idx[:], index)
+	h.Write(idx[:])
+	h.Write(prev)
+	h.Write(entry)
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,140 @@
+package log_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/codahale/sskg"
+	"github.com/codahale/sskg/log"
+)
+
+func TestAppendAndVerify(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	l := log.New(seq, sha256.New)
+
+	var records []log.Record
+	for i := 0; i < 10; i++ {
+		records = append(records, l.Append([]byte("entry")))
+	}
+
+	seq = sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	if err := log.New(seq, sha256.New).Verify(records, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyAt(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	l := log.New(seq, sha256.New)
+
+	var records []log.Record
+	for i := 0; i < 10; i++ {
+		records = append(records, l.Append([]byte("entry")))
+	}
+
+	seq = sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	v := log.New(seq, sha256.New)
+	for i := range records {
+		if err := v.VerifyAt(records, i); err != nil {
+			t.Errorf("record %d: %v", i, err)
+		}
+	}
+}
+
+func TestVerifyAtOutOfRange(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	l := log.New(seq, sha256.New)
+
+	records := []log.Record{l.Append([]byte("entry"))}
+
+	if err := l.VerifyAt(records, 1); err == nil {
+		t.Fatal("expected an error for an out-of-range index")
+	}
+}
+
+func TestVerifyAtTruncatedSegment(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	l := log.New(seq, sha256.New)
+
+	var records []log.Record
+	for i := 0; i < 10; i++ {
+		records = append(records, l.Append([]byte("entry")))
+	}
+	records = records[5:]
+
+	seq = sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	v := log.New(seq, sha256.New)
+	if err := v.VerifyAt(records, 0); err == nil {
+		t.Fatal("expected an error because record 4 is missing")
+	}
+}
+
+func TestVerifyFromStartIndex(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	l := log.New(seq, sha256.New)
+
+	var records []log.Record
+	for i := 0; i < 10; i++ {
+		records = append(records, l.Append([]byte("entry")))
+	}
+
+	seq = sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	v := log.New(seq, sha256.New)
+	if err := v.Verify(records, 5); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyMissingStartIndexPredecessor(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	l := log.New(seq, sha256.New)
+
+	var records []log.Record
+	for i := 0; i < 10; i++ {
+		records = append(records, l.Append([]byte("entry")))
+	}
+	records = records[5:]
+
+	seq = sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	if err := log.New(seq, sha256.New).Verify(records, 5); err == nil {
+		t.Fatal("expected an error because record 4 is missing")
+	}
+}
+
+func TestVerifyTamperedEntry(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	l := log.New(seq, sha256.New)
+
+	records := []log.Record{l.Append([]byte("entry"))}
+	records[0].Entry = []byte("tampered")
+
+	seq = sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	if err := log.New(seq, sha256.New).Verify(records, 0); err == nil {
+		t.Fatal("expected verification failure")
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<10)
+	l := log.New(seq, sha256.New)
+
+	var buf bytes.Buffer
+	enc := log.NewEncoder(&buf)
+	for i := 0; i < 3; i++ {
+		if err := enc.Encode(l.Append([]byte("entry"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dec := log.NewDecoder(&buf)
+	for i := 0; i < 3; i++ {
+		r, err := dec.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Index != uint64(i) {
+			t.Errorf("Index was %d, but expected %d", r.Index, i)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package merkle provides an RFC 6962-style Merkle tree over a sequence of
+// leaves, giving a verifier a single root plus a short audit path to check
+// that a given leaf was included in the tree without hashing the whole
+// sequence. Paired with sskg.Seq.Seek, this gives an auditor an O(log N) way
+// to check both a log record's MAC and its position in the committed log.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"hash"
+)
+
+// A Tree is a Merkle tree over a fixed sequence of leaves.
+type Tree struct {
+	alg    func() hash.Hash
+	leaves [][]byte
+}
+
+// New returns a Tree over leaves, hashed with alg.
+func New(alg func() hash.Hash, leaves [][]byte) *Tree {
+	return &Tree{alg: alg, leaves: leaves}
+}
+
+// NewSHA256 returns a Tree over leaves, hashed with SHA-256.
+func NewSHA256(leaves [][]byte) *Tree {
+	return New(sha256.New, leaves)
+}
+
+// Root returns the root hash of the tree.
+func (t *Tree) Root() []byte {
+	return subtreeHash(t.alg, t.leaves)
+}
+
+// InclusionProof returns the audit path proving that the leaf at index i is
+// included in the tree, ordered from the leaf's sibling up to the root.
+func (t *Tree) InclusionProof(i int) ([][]byte, error) {
+	if i < 0 || i >= len(t.leaves) {
+		return nil, errors.New("merkle: index out of range")
+	}
+
+	return auditPath(t.alg, t.leaves, i), nil
+}
+
+// VerifyInclusion reports whether proof is a valid audit path proving that
+// leaf is the i-th of n leaves committed to by root.
+func VerifyInclusion(alg func() hash.Hash, root, leaf []byte, proof [][]byte, i, n int) bool {
+	if i < 0 || i >= n {
+		return false
+	}
+
+	got := reconstructRoot(alg, leafHash(alg, leaf), proof, i, n)
+	return got != nil && bytes.Equal(got, root)
+}
+
+func subtreeHash(alg func() hash.Hash, leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		return alg().Sum(nil)
+	case 1:
+		return leafHash(alg, leaves[0])
+	default:
+		k := splitPoint(len(leaves))
+		return nodeHash(alg, subtreeHash(alg, leaves[:k]), subtreeHash(alg, leaves[k:]))
+	}
+}
+
+// auditPath returns the sibling hashes needed to recompute the root of
+// leaves from the hash of leaves[i], ordered leaf-to-root.
+func auditPath(alg func() hash.Hash, leaves [][]byte, i int) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+
+	k := splitPoint(len(leaves))
+	if i < k {
+		return append(auditPath(alg, leaves[:k], i), subtreeHash(alg, leaves[k:]))
+	}
+
+	return append(auditPath(alg, leaves[k:], i-k), subtreeHash(alg, leaves[:k]))
+}
+
+// reconstructRoot replays proof against leafHash, mirroring the splits
+// auditPath used to produce it.
+func reconstructRoot(alg func() hash.Hash, leafHash []byte, proof [][]byte, i, n int) []byte {
+	if n <= 1 {
+		return leafHash
+	}
+
+	if len(proof) == 0 {
+		return nil
+	}
+
+	sibling, rest := proof[len(proof)-1], proof[:len(proof)-1]
+	k := splitPoint(n)
+
+	if i < k {
+		return nodeHash(alg, reconstructRoot(alg, leafHash, rest, i, k), sibling)
+	}
+
+	return nodeHash(alg, sibling, reconstructRoot(alg, leafHash, rest, i-k, n-k))
+}
+
+// splitPoint returns the largest power of two strictly less than n, the
+// point at which RFC 6962 splits a list of n>1 leaves so that the left
+// subtree is always full.
+func splitPoint(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+
+	return k
+}
+
+func leafHash(alg func() hash.Hash, leaf []byte) []byte {
+	h := alg()
+	h.Write([]byte{0x00})
+	h.Write(leaf)
+
+	return h.Sum(nil)
+}
+
+func nodeHash(alg func() hash.Hash, left, right []byte) []byte {
+	h := alg()
+	h.Write([]byte{0x01})
+	h.Write(left)
+	h.Write(right)
+
+	return h.Sum(nil)
+}
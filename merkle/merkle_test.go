@@ -0,0 +1,69 @@
+package merkle_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/codahale/sskg/merkle"
+)
+
+func leaves(n int) [][]byte {
+	out := make([][]byte, n)
+	for i := range out {
+		out[i] = []byte{byte(i)}
+	}
+
+	return out
+}
+
+func TestRootStable(t *testing.T) {
+	a := merkle.NewSHA256(leaves(7)).Root()
+	b := merkle.NewSHA256(leaves(7)).Root()
+
+	if !bytes.Equal(a, b) {
+		t.Error("Root was not deterministic")
+	}
+}
+
+func TestInclusionProof(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 8, 13, 32} {
+		ls := leaves(n)
+		tree := merkle.NewSHA256(ls)
+		root := tree.Root()
+
+		for i := range ls {
+			proof, err := tree.InclusionProof(i)
+			if err != nil {
+				t.Fatalf("n=%d i=%d: %v", n, i, err)
+			}
+
+			if !merkle.VerifyInclusion(sha256.New, root, ls[i], proof, i, n) {
+				t.Errorf("n=%d i=%d: proof did not verify", n, i)
+			}
+		}
+	}
+}
+
+func TestInclusionProofRejectsTampering(t *testing.T) {
+	ls := leaves(5)
+	tree := merkle.NewSHA256(ls)
+	root := tree.Root()
+
+	proof, err := tree.InclusionProof(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if merkle.VerifyInclusion(sha256.New, root, []byte("nope"), proof, 2, len(ls)) {
+		t.Error("expected verification to fail for the wrong leaf")
+	}
+}
+
+func TestInclusionProofOutOfRange(t *testing.T) {
+	tree := merkle.NewSHA256(leaves(3))
+
+	if _, err := tree.InclusionProof(3); err == nil {
+		t.Error("expected an error for an out-of-range index")
+	}
+}
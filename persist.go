@@ -0,0 +1,223 @@
+package sskg
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"reflect"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// version identifies the MarshalBinary wire format, so future incompatible
+// changes can be rejected instead of silently misparsed.
+const version = 2
+
+// Algorithm identifiers for the hash registry used by MarshalBinary and
+// UnmarshalBinary. func() hash.Hash values can't be serialized directly, so
+// a Seq's alg must be one of these known constructors to round-trip.
+const (
+	algSHA256 byte = iota + 1
+	algSHA512
+	algSHAKE256
+)
+
+var algsByID = map[byte]func() hash.Hash{
+	algSHA256:   sha256.New,
+	algSHA512:   sha512.New,
+	algSHAKE256: Shake256,
+}
+
+var idsByAlg = map[uintptr]byte{
+	funcPointer(sha256.New): algSHA256,
+	funcPointer(sha512.New): algSHA512,
+	funcPointer(Shake256):   algSHAKE256,
+}
+
+func funcPointer(alg func() hash.Hash) uintptr {
+	return reflect.ValueOf(alg).Pointer()
+}
+
+// PRF identifiers for the registry used by MarshalBinary and
+// UnmarshalBinary. A Seq's prf must be one of these known backends to
+// round-trip: without this, a Seq built with WithSHAKE128/WithSHAKE256 would
+// silently come back with the HKDF backend and derive different keys.
+const (
+	prfHKDF byte = iota + 1
+	prfSHAKE128
+	prfSHAKE256
+)
+
+func prfID(p PRF) (byte, error) {
+	switch v := p.(type) {
+	case hkdfPRF:
+		return prfHKDF, nil
+	case shakePRF:
+		switch reflect.ValueOf(v.newCShake).Pointer() {
+		case reflect.ValueOf(sha3.NewCShake128).Pointer():
+			return prfSHAKE128, nil
+		case reflect.ValueOf(sha3.NewCShake256).Pointer():
+			return prfSHAKE256, nil
+		}
+	}
+
+	return 0, errors.New("sskg: prf is not in the PRF registry")
+}
+
+func prfByID(id byte, alg func() hash.Hash) (PRF, error) {
+	switch id {
+	case prfHKDF:
+		return hkdfPRF{alg: alg}, nil
+	case prfSHAKE128:
+		return shakePRF{newCShake: sha3.NewCShake128}, nil
+	case prfSHAKE256:
+		return shakePRF{newCShake: sha3.NewCShake256}, nil
+	default:
+		return nil, errors.New("sskg: unknown PRF")
+	}
+}
+
+// MarshalBinary encodes s's hash algorithm, PRF backend, and node stack, so
+// it can be restored with UnmarshalBinary. The encoding is unauthenticated;
+// to guard against tampering in storage, wrap it with Seal and Open.
+func (s Seq) MarshalBinary() ([]byte, error) {
+	algID, ok := idsByAlg[funcPointer(s.alg)]
+	if !ok {
+		return nil, errors.New("sskg: alg is not in the hash algorithm registry")
+	}
+
+	prfIdent, err := prfID(s.prf)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(version)
+	buf.WriteByte(algID)
+	buf.WriteByte(prfIdent)
+
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(s.nodes)))
+	buf.Write(lenBuf[:n])
+
+	for _, nd := range s.nodes {
+		n := binary.PutUvarint(lenBuf[:], uint64(nd.h))
+		buf.Write(lenBuf[:n])
+
+		n = binary.PutUvarint(lenBuf[:], uint64(len(nd.k)))
+		buf.Write(lenBuf[:n])
+		buf.Write(nd.k)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores s from data produced by MarshalBinary, including
+// the PRF backend it was serialized with.
+func (s *Seq) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+
+	v, err := r.ReadByte()
+	if err != nil {
+		return errors.New("sskg: truncated state")
+	}
+	if v != version {
+		return errors.New("sskg: unsupported state version")
+	}
+
+	algIdent, err := r.ReadByte()
+	if err != nil {
+		return errors.New("sskg: truncated state")
+	}
+
+	alg, ok := algsByID[algIdent]
+	if !ok {
+		return errors.New("sskg: unknown hash algorithm")
+	}
+
+	prfIdent, err := r.ReadByte()
+	if err != nil {
+		return errors.New("sskg: truncated state")
+	}
+
+	prf, err := prfByID(prfIdent, alg)
+	if err != nil {
+		return err
+	}
+
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return errors.New("sskg: truncated state")
+	}
+
+	nodes := make([]node, 0, count)
+	for i := uint64(0); i < count; i++ {
+		h, err := binary.ReadUvarint(r)
+		if err != nil {
+			return errors.New("sskg: truncated state")
+		}
+
+		size, err := binary.ReadUvarint(r)
+		if err != nil {
+			return errors.New("sskg: truncated state")
+		}
+
+		k := make([]byte, size)
+		if _, err := io.ReadFull(r, k); err != nil {
+			return errors.New("sskg: truncated state")
+		}
+
+		nodes = append(nodes, node{k: k, h: uint(h)})
+	}
+
+	s.alg = alg
+	s.prf = prf
+	s.size = alg().Size()
+	s.nodes = nodes
+
+	return nil
+}
+
+// Seal encodes seq with MarshalBinary and authenticates it with an HMAC
+// keyed by wrappingKey, so the result can be stored on disk: an attacker who
+// steals it cannot alter the Seq's position without detection.
+func Seal(wrappingKey []byte, seq Seq) ([]byte, error) {
+	body, err := seq.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, wrappingKey)
+	mac.Write(body)
+
+	return mac.Sum(body), nil
+}
+
+// Open is the counterpart to New for a Seq previously serialized with Seal:
+// it verifies blob's HMAC under wrappingKey before restoring the Seq.
+func Open(wrappingKey, blob []byte) (Seq, error) {
+	if len(blob) < sha256.Size {
+		return Seq{}, errors.New("sskg: truncated state")
+	}
+
+	body, tag := blob[:len(blob)-sha256.Size], blob[len(blob)-sha256.Size:]
+
+	mac := hmac.New(sha256.New, wrappingKey)
+	mac.Write(body)
+
+	if !hmac.Equal(mac.Sum(nil), tag) {
+		return Seq{}, errors.New("sskg: state failed authentication")
+	}
+
+	var s Seq
+	if err := s.UnmarshalBinary(body); err != nil {
+		return Seq{}, err
+	}
+
+	return s, nil
+}
@@ -20,34 +20,44 @@ package sskg
 import (
 	"hash"
 	"math"
-
-	"code.google.com/p/go.crypto/hkdf"
 )
 
 // A Seq is a sequence of forward-secure keys.
 type Seq struct {
 	nodes []node
 	alg   func() hash.Hash
+	prf   PRF
 	size  int
 }
 
-// New creates a new Seq with the given hash algorithm, seed, and maximum number
-// of keys.
-func New(alg func() hash.Hash, seed []byte, maxKeys uint) Seq {
+// New creates a new Seq with the given hash algorithm, seed, and maximum
+// number of keys. By default, child and output keys are derived with HKDF
+// built from alg; pass a PRF-selecting Option (e.g. WithSHAKE256) to use a
+// different backend.
+func New(alg func() hash.Hash, seed []byte, maxKeys uint, opts ...Option) Seq {
 	size := alg().Size()
-	return Seq{
-		nodes: []node{{
-			k: prf(alg, size, []byte("seed"), seed),
-			h: uint(math.Ceil(math.Log2(float64(maxKeys) + 1))),
-		}},
+	s := Seq{
 		alg:  alg,
+		prf:  hkdfPRF{alg: alg},
 		size: size,
 	}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	seedKey := make([]byte, size)
+	s.prf.Derive([]byte("seed"), seed, seedKey)
+	s.push(seedKey, uint(math.Ceil(math.Log2(float64(maxKeys)+1))))
+
+	return s
 }
 
 // Key returns the Seq's current key of the given size.
 func (s Seq) Key(size int) []byte {
-	return prf(s.alg, size, []byte("key"), s.nodes[len(s.nodes)-1].k)
+	out := make([]byte, size)
+	s.prf.Derive([]byte("key"), s.nodes[len(s.nodes)-1].k, out)
+	return out
 }
 
 // Next advances the Seq's current key to the next in the sequence.
@@ -57,8 +67,8 @@ func (s *Seq) Next() {
 	k, h := s.pop()
 
 	if h > 1 {
-		s.push(prf(s.alg, s.size, right, k), h-1)
-		s.push(prf(s.alg, s.size, left, k), h-1)
+		s.push(s.derive(right, k), h-1)
+		s.push(s.derive(left, k), h-1)
 	}
 }
 
@@ -77,11 +87,11 @@ func (s *Seq) Seek(n int) {
 
 		pow := 1 << h
 		if n < pow {
-			s.push(prf(s.alg, s.size, right, k), h)
-			k = prf(s.alg, s.size, left, k)
+			s.push(s.derive(right, k), h)
+			k = s.derive(left, k)
 			n--
 		} else {
-			k = prf(s.alg, s.size, right, k)
+			k = s.derive(right, k)
 			n -= pow
 		}
 	}
@@ -89,6 +99,17 @@ func (s *Seq) Seek(n int) {
 	s.push(k, h)
 }
 
+// Clone returns an independent copy of s. Since s.nodes is a slice, copying a
+// Seq by value shares its backing array: mutating one copy via Next or Seek
+// can silently corrupt the other's node stack. Clone makes a deep copy of the
+// stack so the result and s can be advanced independently.
+func (s Seq) Clone() Seq {
+	nodes := make([]node, len(s.nodes))
+	copy(nodes, s.nodes)
+	s.nodes = nodes
+	return s
+}
+
 func (s *Seq) pop() ([]byte, uint) {
 	node := s.nodes[len(s.nodes)-1]
 	s.nodes = s.nodes[:len(s.nodes)-1]
@@ -109,9 +130,8 @@ var (
 	left  = []byte("left")
 )
 
-func prf(alg func() hash.Hash, size int, label, seed []byte) []byte {
-	buf := make([]byte, size)
-	kdf := hkdf.New(alg, seed, nil, label)
-	_, _ = kdf.Read(buf)
-	return buf
+func (s Seq) derive(label, key []byte) []byte {
+	out := make([]byte, s.size)
+	s.prf.Derive(label, key, out)
+	return out
 }
@@ -14,7 +14,7 @@ func TestNext(t *testing.T) {
 		seq.Next()
 	}
 
-	if v := seq.Key(); !bytes.Equal(expected, v) {
+	if v := seq.Key(32); !bytes.Equal(expected, v) {
 		t.Errorf("Key was %#v, but expected %#v", v, expected)
 	}
 }
@@ -23,11 +23,107 @@ func TestSeek(t *testing.T) {
 	seq := sskg.New(sha256.New, make([]byte, 32), 1<<32)
 	seq.Seek(10000)
 
-	if v := seq.Key(); !bytes.Equal(expected, v) {
+	if v := seq.Key(32); !bytes.Equal(expected, v) {
 		t.Errorf("Key was %#v, but expected %#v", v, expected)
 	}
 }
 
+func TestSHAKEBackend(t *testing.T) {
+	a := sskg.New(sha256.New, make([]byte, 32), 1<<32, sskg.WithSHAKE256())
+	b := sskg.New(sha256.New, make([]byte, 32), 1<<32, sskg.WithSHAKE256())
+
+	for i := 0; i < 100; i++ {
+		a.Next()
+		b.Next()
+	}
+
+	if v, w := a.Key(32), b.Key(32); !bytes.Equal(v, w) {
+		t.Errorf("Key was %#v, but expected %#v", v, w)
+	}
+}
+
+func TestMarshalUnmarshalBinary(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<32)
+	seq.Seek(10000)
+
+	data, err := seq.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var restored sskg.Seq
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, w := seq.Key(32), restored.Key(32); !bytes.Equal(v, w) {
+		t.Errorf("restored Key was %#v, but expected %#v", w, v)
+	}
+
+	seq.Next()
+	restored.Next()
+
+	if v, w := seq.Key(32), restored.Key(32); !bytes.Equal(v, w) {
+		t.Errorf("restored Key after Next was %#v, but expected %#v", w, v)
+	}
+}
+
+func TestMarshalUnmarshalBinaryPreservesSHAKE(t *testing.T) {
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<32, sskg.WithSHAKE256())
+	seq.Seek(10000)
+
+	data, err := seq.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var restored sskg.Seq
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, w := seq.Key(32), restored.Key(32); !bytes.Equal(v, w) {
+		t.Errorf("restored Key was %#v, but expected %#v", w, v)
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	wrappingKey := []byte("a wrapping key")
+
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<32)
+	seq.Seek(10000)
+
+	blob, err := sskg.Seal(wrappingKey, seq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := sskg.Open(wrappingKey, blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, w := seq.Key(32), restored.Key(32); !bytes.Equal(v, w) {
+		t.Errorf("restored Key was %#v, but expected %#v", w, v)
+	}
+}
+
+func TestOpenRejectsTampering(t *testing.T) {
+	wrappingKey := []byte("a wrapping key")
+
+	seq := sskg.New(sha256.New, make([]byte, 32), 1<<32)
+	blob, err := sskg.Seal(wrappingKey, seq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blob[0] ^= 0xff
+
+	if _, err := sskg.Open(wrappingKey, blob); err == nil {
+		t.Fatal("expected tampered state to fail authentication")
+	}
+}
+
 func TestSeekTooFar(t *testing.T) {
 	defer func() {
 		e := recover()